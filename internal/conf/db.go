@@ -1,15 +1,20 @@
 package conf
 
+// DatabaseType names a registered database driver. It is not a closed
+// enum: the sqlite3/mysql/postgres/mssql values below are the built-in
+// drivers, but third parties may register additional ones at import time
+// via db.RegisterDriver.
 type DatabaseType string
 
 const (
 	DatabaseTypeSqlite3  DatabaseType = "sqlite3"
 	DatabaseTypeMysql    DatabaseType = "mysql"
 	DatabaseTypePostgres DatabaseType = "postgres"
+	DatabaseTypeMssql    DatabaseType = "mssql"
 )
 
 type DatabaseConfig struct {
-	Type     DatabaseType `yaml:"type" lc:"default: sqlite3" hc:"support sqlite3, mysql, postgres" env:"DATABASE_TYPE"`
+	Type     DatabaseType `yaml:"type" lc:"default: sqlite3" hc:"built-in: sqlite3, mysql, postgres, mssql; third parties may register more" env:"DATABASE_TYPE"`
 	Host     string       `yaml:"host" hc:"when type is not sqlite3, and port is 0, it will use unix socket file" env:"DATABASE_HOST"`
 	Port     uint16       `yaml:"port" env:"DATABASE_PORT"`
 	User     string       `yaml:"user" env:"DATABASE_USER"`
@@ -19,9 +24,46 @@ type DatabaseConfig struct {
 
 	CustomDSN string `yaml:"custom_dsn" hc:"when not empty, it will ignore other config" env:"DATABASE_CUSTOM_DSN"`
 
-	MaxIdleConns    int    `yaml:"max_idle_conns" lc:"default: 4" hc:"the maximum number of connections in the idle connection pool." env:"DATABASE_MAX_IDLE_CONNS"`
-	MaxOpenConns    int    `yaml:"max_open_conns" lc:"default: 64" hc:"the maximum number of open connections to the database." env:"DATABASE_MAX_OPEN_CONNS"`
+	MaxIdleConns    int    `yaml:"max_idle_conns" hc:"the maximum number of connections in the idle connection pool. when <= 0, it is derived from max_open_conns_multiplier" env:"DATABASE_MAX_IDLE_CONNS"`
+	MaxOpenConns    int    `yaml:"max_open_conns" hc:"the maximum number of open connections to the database. when <= 0, it is derived from max_open_conns_multiplier" env:"DATABASE_MAX_OPEN_CONNS"`
 	ConnMaxLifetime string `yaml:"conn_max_lifetime" lc:"default: 1h" hc:"maximum amount of time a connection may be reused." env:"DATABASE_CONN_MAX_LIFETIME"`
+
+	MaxOpenConnsMultiplier int `yaml:"max_open_conns_multiplier" lc:"default: 8" hc:"when max_open_conns is <= 0, the effective value is max(1, multiplier * runtime.NumCPU()); max_idle_conns defaults to half of that." env:"DATABASE_MAX_OPEN_CONNS_MULTIPLIER"`
+
+	Sqlite SqliteConfig `yaml:"sqlite" hc:"tuning knobs applied as PRAGMA statements when type is sqlite3"`
+
+	KeepAliveInterval string `yaml:"keep_alive_interval" lc:"default: 5m" hc:"interval between background SELECT 1 keepalive checks; readyz consults their result instead of pinging the database on every probe. 0 disables the keepalive goroutine" env:"DATABASE_KEEP_ALIVE_INTERVAL"`
+
+	Options map[string]string `yaml:"options" hc:"driver-specific options passed through verbatim to the registered driver's open func"`
+
+	TLS TLSConfig `yaml:"tls" hc:"mTLS/CA pinning for mysql and postgres; required by managed cloud DBs (RDS, Cloud SQL) that reject plain ssl_mode toggling"`
+}
+
+// TLSConfig loads certificate material for connecting to mysql/postgres
+// over TLS with a pinned CA and/or a client certificate, rather than the
+// coarse on/off/verify toggling that ssl_mode alone provides.
+type TLSConfig struct {
+	CACert             string `yaml:"ca_cert" hc:"path to a PEM-encoded CA certificate bundle used to verify the server" env:"DATABASE_TLS_CA_CERT"`
+	ClientCert         string `yaml:"client_cert" hc:"path to a PEM-encoded client certificate, for mTLS" env:"DATABASE_TLS_CLIENT_CERT"`
+	ClientKey          string `yaml:"client_key" hc:"path to the PEM-encoded private key matching client_cert" env:"DATABASE_TLS_CLIENT_KEY"`
+	ServerName         string `yaml:"server_name" hc:"overrides the server name used for certificate verification; for postgres, host becomes libpq's hostaddr and must be a numeric IP when this is set" env:"DATABASE_TLS_SERVER_NAME"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" hc:"disable server certificate verification; do not use in production" env:"DATABASE_TLS_INSECURE_SKIP_VERIFY"`
+}
+
+// Enabled reports whether any TLS material was configured.
+func (c TLSConfig) Enabled() bool {
+	return c.CACert != "" || c.ClientCert != "" || c.ClientKey != "" || c.InsecureSkipVerify
+}
+
+// SqliteConfig holds PRAGMA tuning knobs applied to every pooled SQLite
+// connection. SQLite PRAGMAs are per-connection, so these are reapplied via
+// a ConnectHook rather than run once at startup.
+type SqliteConfig struct {
+	JournalMode string `yaml:"journal_mode" lc:"default: WAL" hc:"PRAGMA journal_mode, e.g. WAL, DELETE, TRUNCATE" env:"DATABASE_SQLITE_JOURNAL_MODE"`
+	Synchronous string `yaml:"synchronous" lc:"default: NORMAL" hc:"PRAGMA synchronous, e.g. OFF, NORMAL, FULL" env:"DATABASE_SQLITE_SYNCHRONOUS"`
+	CacheSize   string `yaml:"cache_size" lc:"default: 64MiB" hc:"PRAGMA cache_size, as a byte-size string; negative KiB is used internally to request a size instead of a page count" env:"DATABASE_SQLITE_CACHE_SIZE"`
+	BusyTimeout string `yaml:"busy_timeout" lc:"default: 30s" hc:"PRAGMA busy_timeout" env:"DATABASE_SQLITE_BUSY_TIMEOUT"`
+	MmapSize    string `yaml:"mmap_size" lc:"default: 0" hc:"PRAGMA mmap_size, as a byte-size string; 0 disables mmap I/O" env:"DATABASE_SQLITE_MMAP_SIZE"`
 }
 
 func DefaultDatabaseConfig() DatabaseConfig {
@@ -31,8 +73,18 @@ func DefaultDatabaseConfig() DatabaseConfig {
 		DBName:  "synctv",
 		SslMode: "disable",
 
-		MaxIdleConns:    4,
-		MaxOpenConns:    64,
 		ConnMaxLifetime: "1h",
+
+		MaxOpenConnsMultiplier: 8,
+
+		Sqlite: SqliteConfig{
+			JournalMode: "WAL",
+			Synchronous: "NORMAL",
+			CacheSize:   "64MiB",
+			BusyTimeout: "30s",
+			MmapSize:    "0",
+		},
+
+		KeepAliveInterval: "5m",
 	}
 }
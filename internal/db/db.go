@@ -0,0 +1,190 @@
+// Package db manages the lifetime of the application's database connection.
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/synctv-org/synctv/internal/conf"
+	"gorm.io/gorm"
+)
+
+var (
+	db *gorm.DB
+
+	// resolvedPool records the pool limits actually applied by the last
+	// call to Init, after CPU-multiplier resolution, so operators can
+	// verify tuning via Ready.
+	resolvedPool PoolStats
+
+	// keepAliveCancel stops the keepalive goroutine started by the last
+	// call to Init, if any.
+	keepAliveCancel context.CancelFunc
+
+	// driverInfo caches the driver type and server version resolved at
+	// Init time so Ready can surface them without querying on every probe.
+	driverInfo DriverInfo
+)
+
+// DriverInfo describes the database driver and server version in use.
+type DriverInfo struct {
+	Type    conf.DatabaseType
+	Version string
+}
+
+// ResolvedDriver returns the driver type and version resolved by the last
+// call to Init.
+func ResolvedDriver() DriverInfo {
+	return driverInfo
+}
+
+// PoolStats describes the connection pool limits resolved at Init time.
+type PoolStats struct {
+	MaxOpenConns int
+	MaxIdleConns int
+}
+
+// ResolvedPool returns the connection pool limits resolved by the last
+// call to Init.
+func ResolvedPool() PoolStats {
+	return resolvedPool
+}
+
+// Init opens the database configured by cfg, applies the connection pool
+// settings and stores the resulting handle for later retrieval via DB.
+func Init(cfg conf.DatabaseConfig) error {
+	dialector, err := dialector(cfg)
+	if err != nil {
+		return err
+	}
+
+	gormDB, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying *sql.DB: %w", err)
+	}
+
+	if err := configurePool(sqlDB, cfg, isSqliteMemory(cfg)); err != nil {
+		return err
+	}
+
+	db = gormDB
+	driverInfo = DriverInfo{Type: cfg.Type, Version: serverVersion(sqlDB, cfg.Type)}
+
+	if keepAliveCancel != nil {
+		keepAliveCancel()
+	}
+	keepAliveCancel = func() {}
+	interval, err := time.ParseDuration(cfg.KeepAliveInterval)
+	if err != nil {
+		return fmt.Errorf("failed to parse keep_alive_interval: %w", err)
+	}
+	if interval > 0 {
+		health.setEnabled(true)
+		ctx, cancel := context.WithCancel(context.Background())
+		keepAliveCancel = cancel
+		startKeepAlive(ctx, sqlDB, interval)
+	} else {
+		// KeepAliveInterval <= 0 is a documented way to disable the
+		// goroutine; Ready falls back to a direct ping in that case instead
+		// of reading a LastOK that will never be populated.
+		health.setEnabled(false)
+	}
+
+	return nil
+}
+
+// Close stops the keepalive goroutine started by Init, if any, and
+// deregisters the mysql TLS config Init registered, if any. The sqlite
+// driver Init registers with database/sql is not released.
+func Close() {
+	if keepAliveCancel != nil {
+		keepAliveCancel()
+	}
+	deregisterMysqlTLSConfig()
+}
+
+func configurePool(sqlDB interface {
+	SetMaxIdleConns(int)
+	SetMaxOpenConns(int)
+	SetConnMaxLifetime(time.Duration)
+}, cfg conf.DatabaseConfig, forceSingleConn bool,
+) error {
+	maxOpen, maxIdle := resolvePoolLimits(cfg)
+	if forceSingleConn {
+		// Sharing an in-memory SQLite database across multiple pooled
+		// connections causes spurious "table not found" errors, since each
+		// connection otherwise sees its own private database.
+		maxOpen, maxIdle = 1, 1
+	}
+
+	sqlDB.SetMaxOpenConns(maxOpen)
+	sqlDB.SetMaxIdleConns(maxIdle)
+	resolvedPool = PoolStats{MaxOpenConns: maxOpen, MaxIdleConns: maxIdle}
+
+	lifetime, err := time.ParseDuration(cfg.ConnMaxLifetime)
+	if err != nil {
+		return fmt.Errorf("failed to parse conn_max_lifetime: %w", err)
+	}
+	sqlDB.SetConnMaxLifetime(lifetime)
+
+	return nil
+}
+
+// resolvePoolLimits applies the CPU-multiplier tuning: when MaxOpenConns is
+// left unset (<= 0), it is derived from MaxOpenConnsMultiplier * NumCPU so
+// admins don't have to hand-tune pool sizes for every deployment size.
+func resolvePoolLimits(cfg conf.DatabaseConfig) (maxOpen, maxIdle int) {
+	multiplier := cfg.MaxOpenConnsMultiplier
+	if multiplier <= 0 {
+		multiplier = 8
+	}
+
+	maxOpen = cfg.MaxOpenConns
+	if maxOpen <= 0 {
+		maxOpen = max(1, multiplier*runtime.NumCPU())
+	}
+
+	maxIdle = cfg.MaxIdleConns
+	if maxIdle <= 0 {
+		maxIdle = max(1, multiplier*runtime.NumCPU()/2)
+	}
+
+	return maxOpen, maxIdle
+}
+
+// serverVersion best-effort queries the version string reported by the
+// underlying database server/library, returning "" on failure.
+func serverVersion(sqlDB *sql.DB, dbType conf.DatabaseType) string {
+	var query string
+	switch dbType {
+	case conf.DatabaseTypeSqlite3:
+		query = "SELECT sqlite_version()"
+	case conf.DatabaseTypeMysql, conf.DatabaseTypePostgres:
+		query = "SELECT version()"
+	case conf.DatabaseTypeMssql:
+		query = "SELECT @@VERSION"
+	default:
+		return ""
+	}
+
+	var version string
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sqlDB.QueryRowContext(ctx, query).Scan(&version); err != nil {
+		return ""
+	}
+	return version
+}
+
+// DB returns the process-wide database handle initialized by Init.
+func DB() *gorm.DB {
+	return db
+}
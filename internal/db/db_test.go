@@ -0,0 +1,54 @@
+package db
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/synctv-org/synctv/internal/conf"
+)
+
+func TestResolvePoolLimits(t *testing.T) {
+	cpu := runtime.NumCPU()
+
+	tests := []struct {
+		name     string
+		cfg      conf.DatabaseConfig
+		wantOpen int
+		wantIdle int
+	}{
+		{
+			name:     "explicit limits are used verbatim",
+			cfg:      conf.DatabaseConfig{MaxOpenConns: 10, MaxIdleConns: 5},
+			wantOpen: 10,
+			wantIdle: 5,
+		},
+		{
+			name:     "unset limits derive from the default multiplier",
+			cfg:      conf.DatabaseConfig{},
+			wantOpen: max(1, 8*cpu),
+			wantIdle: max(1, 8*cpu/2),
+		},
+		{
+			name:     "unset limits derive from a custom multiplier",
+			cfg:      conf.DatabaseConfig{MaxOpenConnsMultiplier: 2},
+			wantOpen: max(1, 2*cpu),
+			wantIdle: max(1, 2*cpu/2),
+		},
+		{
+			name:     "non-positive multiplier falls back to the default",
+			cfg:      conf.DatabaseConfig{MaxOpenConnsMultiplier: -1},
+			wantOpen: max(1, 8*cpu),
+			wantIdle: max(1, 8*cpu/2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOpen, gotIdle := resolvePoolLimits(tt.cfg)
+			if gotOpen != tt.wantOpen || gotIdle != tt.wantIdle {
+				t.Errorf("resolvePoolLimits(%+v) = (%d, %d), want (%d, %d)",
+					tt.cfg, gotOpen, gotIdle, tt.wantOpen, tt.wantIdle)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/synctv-org/synctv/internal/conf"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+)
+
+// openSqlite3 is the built-in OpenFunc for conf.DatabaseTypeSqlite3.
+func openSqlite3(cfg conf.DatabaseConfig) (gorm.Dialector, error) {
+	return sqliteDialector(sqliteDSN(cfg), cfg.Sqlite)
+}
+
+// mysqlTLSConfigSeq makes the name registered with mysql.RegisterTLSConfig
+// unique per Init call, since the go-sql-driver forbids re-registering the
+// same name with a different *tls.Config.
+var mysqlTLSConfigSeq int64
+
+// mysqlTLSConfigName is the name last registered with
+// mysqldriver.RegisterTLSConfig, if any. It is deregistered before a new
+// one is registered (and on Close) so reconnecting/reloading config
+// repeatedly doesn't leak an entry per Init for the life of the process.
+var mysqlTLSConfigName string
+
+// openMysql is the built-in OpenFunc for conf.DatabaseTypeMysql.
+func openMysql(cfg conf.DatabaseConfig) (gorm.Dialector, error) {
+	dsn := cfg.CustomDSN
+	if dsn == "" {
+		dsn = mysqlDSN(cfg)
+		if cfg.TLS.Enabled() {
+			tlsConfig, err := buildTLSConfig(cfg.TLS)
+			if err != nil {
+				return nil, err
+			}
+
+			deregisterMysqlTLSConfig()
+
+			name := fmt.Sprintf("synctv-%d", atomic.AddInt64(&mysqlTLSConfigSeq, 1))
+			if err := mysqldriver.RegisterTLSConfig(name, tlsConfig); err != nil {
+				return nil, fmt.Errorf("failed to register mysql tls config: %w", err)
+			}
+			mysqlTLSConfigName = name
+			dsn += "&tls=" + name
+		}
+	}
+	return mysql.Open(dsn), nil
+}
+
+// deregisterMysqlTLSConfig removes the previously registered mysql TLS
+// config, if any.
+func deregisterMysqlTLSConfig() {
+	if mysqlTLSConfigName == "" {
+		return
+	}
+	mysqldriver.DeregisterTLSConfig(mysqlTLSConfigName)
+	mysqlTLSConfigName = ""
+}
+
+// openPostgres is the built-in OpenFunc for conf.DatabaseTypePostgres.
+func openPostgres(cfg conf.DatabaseConfig) (gorm.Dialector, error) {
+	dsn := cfg.CustomDSN
+	if dsn == "" {
+		dsn = postgresDSN(cfg)
+	}
+	return postgres.Open(dsn), nil
+}
+
+// openMssql is the built-in OpenFunc for conf.DatabaseTypeMssql.
+func openMssql(cfg conf.DatabaseConfig) (gorm.Dialector, error) {
+	dsn := cfg.CustomDSN
+	if dsn == "" {
+		dsn = mssqlDSN(cfg)
+	}
+	return sqlserver.Open(dsn), nil
+}
+
+// sqliteDSN returns the DSN used to open cfg's SQLite database, honoring
+// CustomDSN when set.
+func sqliteDSN(cfg conf.DatabaseConfig) string {
+	if cfg.CustomDSN != "" {
+		return cfg.CustomDSN
+	}
+	return cfg.DBName
+}
+
+// isSqliteMemory reports whether cfg addresses an in-memory SQLite
+// database.
+func isSqliteMemory(cfg conf.DatabaseConfig) bool {
+	return cfg.Type == conf.DatabaseTypeSqlite3 && isSqliteMemoryDSN(sqliteDSN(cfg))
+}
+
+func mysqlDSN(cfg conf.DatabaseConfig) string {
+	return fmt.Sprintf(
+		"%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName,
+	)
+}
+
+// postgresDSN builds the libpq keyword/value DSN for cfg. When cfg.TLS
+// pins a CA and/or client certificate, sslmode is upgraded to verify
+// against them instead of the plain cfg.SslMode toggle, since SslMode alone
+// can't express cert pinning for managed databases like RDS or Cloud SQL.
+func postgresDSN(cfg conf.DatabaseConfig) string {
+	dsn := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SslMode,
+	)
+
+	if !cfg.TLS.Enabled() {
+		return dsn
+	}
+
+	sslMode := "verify-ca"
+	switch {
+	case cfg.TLS.InsecureSkipVerify:
+		sslMode = "require"
+	case cfg.TLS.CACert != "":
+		sslMode = "verify-full"
+	}
+	dsn = fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, sslMode,
+	)
+
+	if cfg.TLS.ServerName != "" {
+		// libpq verifies the server certificate (and sends SNI) against
+		// "host", and dials "hostaddr" instead when both are set. Swapping
+		// them here overrides the verified name without changing where the
+		// connection actually goes, the same decoupling ServerName gives
+		// buildTLSConfig's *tls.Config for mysql.
+		dsn = fmt.Sprintf(
+			"host=%s hostaddr=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+			cfg.TLS.ServerName, cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, sslMode,
+		)
+	}
+
+	if cfg.TLS.CACert != "" {
+		dsn += " sslrootcert=" + cfg.TLS.CACert
+	}
+	if cfg.TLS.ClientCert != "" {
+		dsn += " sslcert=" + cfg.TLS.ClientCert
+	}
+	if cfg.TLS.ClientKey != "" {
+		dsn += " sslkey=" + cfg.TLS.ClientKey
+	}
+
+	return dsn
+}
+
+func mssqlDSN(cfg conf.DatabaseConfig) string {
+	return fmt.Sprintf(
+		"sqlserver://%s:%s@%s:%d?database=%s",
+		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName,
+	)
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// healthStatus is the package-level health registry populated by the
+// keepalive goroutine. Ready consults it instead of issuing a synchronous
+// Ping on every probe, which can cascade failures under load on Kubernetes.
+type healthStatus struct {
+	mu                  sync.RWMutex
+	enabled             bool
+	lastOK              time.Time
+	consecutiveFailures int
+}
+
+var health healthStatus
+
+// HealthStatus is a snapshot of the keepalive registry.
+type HealthStatus struct {
+	// Enabled reports whether the keepalive goroutine is running for the
+	// current Init. When false (KeepAliveInterval <= 0), LastOK is never
+	// populated and callers should fall back to a direct ping instead of
+	// treating a zero LastOK as unhealthy.
+	Enabled             bool
+	LastOK              time.Time
+	ConsecutiveFailures int
+}
+
+// Health returns the last recorded keepalive result.
+func Health() HealthStatus {
+	health.mu.RLock()
+	defer health.mu.RUnlock()
+	return HealthStatus{
+		Enabled:             health.enabled,
+		LastOK:              health.lastOK,
+		ConsecutiveFailures: health.consecutiveFailures,
+	}
+}
+
+// setEnabled records whether the keepalive goroutine is running, and
+// resets any previous result so a stale LastOK from a prior Init isn't
+// mistaken for a live one.
+func (h *healthStatus) setEnabled(enabled bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.enabled = enabled
+	h.lastOK = time.Time{}
+	h.consecutiveFailures = 0
+}
+
+func (h *healthStatus) recordSuccess() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastOK = time.Now()
+	h.consecutiveFailures = 0
+}
+
+func (h *healthStatus) recordFailure() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.consecutiveFailures++
+}
+
+// startKeepAlive runs a best-effort "SELECT 1" against sqlDB every interval
+// and records the outcome in the package-level health registry. It exits
+// when ctx is cancelled.
+func startKeepAlive(ctx context.Context, sqlDB *sql.DB, interval time.Duration) {
+	keepAliveCheck(ctx, sqlDB)
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				keepAliveCheck(ctx, sqlDB)
+			}
+		}
+	}()
+}
+
+func keepAliveCheck(ctx context.Context, sqlDB *sql.DB) {
+	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := sqlDB.ExecContext(checkCtx, "SELECT 1"); err != nil {
+		health.recordFailure()
+		return
+	}
+
+	health.recordSuccess()
+}
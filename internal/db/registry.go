@@ -0,0 +1,49 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/synctv-org/synctv/internal/conf"
+	"gorm.io/gorm"
+)
+
+// OpenFunc builds the gorm.Dialector for a registered database driver.
+type OpenFunc func(cfg conf.DatabaseConfig) (gorm.Dialector, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[conf.DatabaseType]OpenFunc{}
+)
+
+// RegisterDriver registers an OpenFunc under name, making conf.DatabaseType
+// an open set rather than a closed enum. Third parties can call this from
+// an init() at import time to add support for a custom database driver.
+// Registering an already-registered name overwrites it.
+func RegisterDriver(name conf.DatabaseType, open OpenFunc) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = open
+}
+
+func lookupDriver(name conf.DatabaseType) (OpenFunc, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	open, ok := registry[name]
+	return open, ok
+}
+
+func init() {
+	RegisterDriver(conf.DatabaseTypeSqlite3, openSqlite3)
+	RegisterDriver(conf.DatabaseTypeMysql, openMysql)
+	RegisterDriver(conf.DatabaseTypePostgres, openPostgres)
+	RegisterDriver(conf.DatabaseTypeMssql, openMssql)
+}
+
+func dialector(cfg conf.DatabaseConfig) (gorm.Dialector, error) {
+	open, ok := lookupDriver(cfg.Type)
+	if !ok {
+		return nil, fmt.Errorf("unregistered database type: %s", cfg.Type)
+	}
+	return open(cfg)
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/synctv-org/synctv/internal/conf"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteDriverSeq makes the registered driver name unique per Init call,
+// since database/sql forbids registering the same driver name twice.
+//
+// database/sql has no Deregister counterpart, so every Init/reconnect adds
+// one permanent entry to its process-wide driver registry for the life of
+// the process. This is expected to be bounded by the (small) number of
+// times Init is called, typically once at startup; it is not suitable for
+// a hot config-reload path that re-opens SQLite frequently.
+var sqliteDriverSeq int64
+
+// sqliteDialector builds a gorm sqlite.Dialector backed by a driver whose
+// ConnectHook applies the configured PRAGMA tuning to every pooled
+// connection, since SQLite PRAGMAs are per-connection for journal_mode,
+// synchronous, cache_size, busy_timeout and mmap_size.
+func sqliteDialector(dsn string, cfg conf.SqliteConfig) (gorm.Dialector, error) {
+	pragmas, err := sqlitePragmas(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	driverName := fmt.Sprintf("sqlite3_synctv_%d", atomic.AddInt64(&sqliteDriverSeq, 1))
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			for _, pragma := range pragmas {
+				if _, err := conn.Exec(pragma, nil); err != nil {
+					return fmt.Errorf("failed to apply %q: %w", pragma, err)
+				}
+			}
+			return nil
+		},
+	})
+
+	return sqlite.Dialector{DSN: dsn, DriverName: driverName}, nil
+}
+
+func sqlitePragmas(cfg conf.SqliteConfig) ([]string, error) {
+	cacheSize, err := sqliteCacheSizePragma(cfg.CacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sqlite cache_size: %w", err)
+	}
+
+	mmapSize, err := parseByteSize(cfg.MmapSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sqlite mmap_size: %w", err)
+	}
+
+	busyTimeout, err := time.ParseDuration(cfg.BusyTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sqlite busy_timeout: %w", err)
+	}
+
+	return []string{
+		fmt.Sprintf("PRAGMA journal_mode = %s", cfg.JournalMode),
+		fmt.Sprintf("PRAGMA synchronous = %s", cfg.Synchronous),
+		fmt.Sprintf("PRAGMA cache_size = %d", cacheSize),
+		fmt.Sprintf("PRAGMA busy_timeout = %d", busyTimeout.Milliseconds()),
+		fmt.Sprintf("PRAGMA mmap_size = %d", mmapSize),
+	}, nil
+}
+
+// sqliteCacheSizePragma translates a byte-size string into the value
+// expected by PRAGMA cache_size: a negative number of KiB, as documented by
+// SQLite ("if N is negative, the number of pages is adjusted to use
+// approximately abs(N*1024) bytes").
+func sqliteCacheSizePragma(s string) (int64, error) {
+	bytes, err := parseByteSize(s)
+	if err != nil {
+		return 0, err
+	}
+	return -(bytes / 1024), nil
+}
+
+// isSqliteMemoryDSN reports whether dsn addresses an in-memory SQLite
+// database (":memory:" or a "file::memory:" / "mode=memory" DSN).
+func isSqliteMemoryDSN(dsn string) bool {
+	lower := strings.ToLower(dsn)
+	return lower == ":memory:" || strings.Contains(lower, ":memory:") || strings.Contains(lower, "mode=memory")
+}
+
+var byteSizeUnits = map[string]int64{
+	"b":   1,
+	"kib": 1 << 10,
+	"kb":  1 << 10,
+	"mib": 1 << 20,
+	"mb":  1 << 20,
+	"gib": 1 << 30,
+	"gb":  1 << 30,
+}
+
+// parseByteSize parses strings like "64MiB", "0" or "512KB" into a byte
+// count.
+func parseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+
+	i := 0
+	for i < len(s) && (s[i] == '-' || s[i] == '.' || (s[i] >= '0' && s[i] <= '9')) {
+		i++
+	}
+
+	numPart, unitPart := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	value, err := strconv.ParseFloat(numPart, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	if unitPart == "" {
+		return int64(value), nil
+	}
+
+	unit, ok := byteSizeUnits[unitPart]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q", unitPart)
+	}
+
+	return int64(value * float64(unit)), nil
+}
@@ -0,0 +1,93 @@
+package db
+
+import "testing"
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "", want: 0},
+		{in: "0", want: 0},
+		{in: "512", want: 512},
+		{in: "64MiB", want: 64 << 20},
+		{in: "64MB", want: 64 << 20},
+		{in: "1GiB", want: 1 << 30},
+		{in: "512KB", want: 512 << 10},
+		{in: "1.5KiB", want: int64(1.5 * (1 << 10))},
+		{in: "  64 MiB  ", want: 64 << 20},
+		{in: "bogus", wantErr: true},
+		{in: "64TiB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseByteSize(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseByteSize(%q) = %d, nil; want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseByteSize(%q) unexpected error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseByteSize(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSqliteCacheSizePragma(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "0", want: 0},
+		{in: "64MiB", want: -(64 << 20 / 1024)},
+		{in: "2000", want: -(2000 / 1024)},
+		{in: "bogus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := sqliteCacheSizePragma(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("sqliteCacheSizePragma(%q) = %d, nil; want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sqliteCacheSizePragma(%q) unexpected error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("sqliteCacheSizePragma(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSqliteMemoryDSN(t *testing.T) {
+	tests := []struct {
+		dsn  string
+		want bool
+	}{
+		{":memory:", true},
+		{"file::memory:?cache=shared", true},
+		{"file:test.db?mode=memory&cache=shared", true},
+		{"synctv.db", false},
+		{"file:synctv.db", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			if got := isSqliteMemoryDSN(tt.dsn); got != tt.want {
+				t.Errorf("isSqliteMemoryDSN(%q) = %v, want %v", tt.dsn, got, tt.want)
+			}
+		})
+	}
+}
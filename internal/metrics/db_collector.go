@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/synctv-org/synctv/internal/db"
+)
+
+// dbStatsCollector reports sql.DBStats for the process-wide database handle
+// on every scrape, rather than polling on a timer.
+type dbStatsCollector struct {
+	openConnections   *prometheus.Desc
+	inUse             *prometheus.Desc
+	idle              *prometheus.Desc
+	waitCount         *prometheus.Desc
+	waitDuration      *prometheus.Desc
+	maxIdleClosed     *prometheus.Desc
+	maxLifetimeClosed *prometheus.Desc
+}
+
+func newDBStatsCollector() *dbStatsCollector {
+	return &dbStatsCollector{
+		openConnections:   prometheus.NewDesc("synctv_db_open_connections", "The number of established connections, both in use and idle.", nil, nil),
+		inUse:             prometheus.NewDesc("synctv_db_in_use_connections", "The number of connections currently in use.", nil, nil),
+		idle:              prometheus.NewDesc("synctv_db_idle_connections", "The number of idle connections.", nil, nil),
+		waitCount:         prometheus.NewDesc("synctv_db_wait_count_total", "The total number of connections waited for.", nil, nil),
+		waitDuration:      prometheus.NewDesc("synctv_db_wait_duration_seconds_total", "The total time blocked waiting for a new connection.", nil, nil),
+		maxIdleClosed:     prometheus.NewDesc("synctv_db_max_idle_closed_total", "The total number of connections closed due to SetMaxIdleConns.", nil, nil),
+		maxLifetimeClosed: prometheus.NewDesc("synctv_db_max_lifetime_closed_total", "The total number of connections closed due to SetConnMaxLifetime.", nil, nil),
+	}
+}
+
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.openConnections
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.maxIdleClosed
+	ch <- c.maxLifetimeClosed
+}
+
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	gormDB := db.DB()
+	if gormDB == nil {
+		return
+	}
+
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		return
+	}
+
+	c.collectStats(ch, sqlDB.Stats())
+}
+
+func (c *dbStatsCollector) collectStats(ch chan<- prometheus.Metric, stats sql.DBStats) {
+	ch <- prometheus.MustNewConstMetric(c.openConnections, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.maxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(c.maxLifetimeClosed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed))
+}
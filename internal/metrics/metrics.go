@@ -0,0 +1,121 @@
+// Package metrics collects Prometheus metrics for HTTP requests, websocket
+// connections, movie-proxy throughput and the database connection pool, so
+// a single synctv instance can be scraped by Prometheus/Grafana.
+package metrics
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	HTTPRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "synctv_http_requests_total",
+			Help: "Total number of HTTP requests handled, partitioned by method, path and status.",
+		},
+		[]string{"method", "path", "status"},
+	)
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "synctv_http_request_duration_seconds",
+			Help:    "Latency of HTTP requests, partitioned by method, path and status.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "path", "status"},
+	)
+
+	WebsocketConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "synctv_websocket_connections",
+			Help: "Number of currently open websocket connections, partitioned by room.",
+		},
+		[]string{"room"},
+	)
+
+	ProxyBytesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "synctv_movie_proxy_bytes_total",
+			Help: "Total bytes proxied for movies, partitioned by direction (in/out).",
+		},
+		[]string{"direction"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		WebsocketConnections,
+		ProxyBytesTotal,
+		newDBStatsCollector(),
+	)
+}
+
+// GinMiddleware records request counts and latencies for every request
+// handled by the gin engine it is attached to.
+func GinMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		status := strconv.Itoa(ctx.Writer.Status())
+		path := ctx.FullPath()
+		if path == "" {
+			path = "unmatched"
+		}
+
+		HTTPRequestsTotal.WithLabelValues(ctx.Request.Method, path, status).Inc()
+		HTTPRequestDuration.WithLabelValues(ctx.Request.Method, path, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+var (
+	roomConnMu    sync.Mutex
+	roomConnCount = map[string]int{}
+)
+
+// IncWebsocketConnections increments the open websocket connection gauge
+// for the given room. Call this from the websocket room's join handler;
+// this package has no such handler of its own to call it from.
+func IncWebsocketConnections(room string) {
+	roomConnMu.Lock()
+	roomConnCount[room]++
+	roomConnMu.Unlock()
+
+	WebsocketConnections.WithLabelValues(room).Inc()
+}
+
+// DecWebsocketConnections decrements the open websocket connection gauge
+// for the given room, pairing IncWebsocketConnections from the room's leave
+// handler (or connection-close cleanup). Once a room's count reaches zero
+// its label is removed, since rooms are created and destroyed continuously
+// and an unpruned GaugeVec would otherwise accumulate unbounded label
+// cardinality for the life of the process.
+func DecWebsocketConnections(room string) {
+	roomConnMu.Lock()
+	roomConnCount[room]--
+	empty := roomConnCount[room] <= 0
+	if empty {
+		delete(roomConnCount, room)
+	}
+	roomConnMu.Unlock()
+
+	if empty {
+		WebsocketConnections.DeleteLabelValues(room)
+		return
+	}
+	WebsocketConnections.WithLabelValues(room).Dec()
+}
+
+// AddProxyBytes adds n to the movie-proxy byte counter for the given
+// direction ("in" or "out"). Call this from the movie-proxy's read/write
+// loop; this package has no such loop of its own to call it from.
+func AddProxyBytes(direction string, n float64) {
+	ProxyBytesTotal.WithLabelValues(direction).Add(n)
+}
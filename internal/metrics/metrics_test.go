@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestWebsocketConnectionsPruneOnZero(t *testing.T) {
+	room := "test-room-prune"
+
+	IncWebsocketConnections(room)
+	IncWebsocketConnections(room)
+	if got := testutil.ToFloat64(WebsocketConnections.WithLabelValues(room)); got != 2 {
+		t.Fatalf("gauge = %v, want 2", got)
+	}
+
+	DecWebsocketConnections(room)
+	if got := testutil.ToFloat64(WebsocketConnections.WithLabelValues(room)); got != 1 {
+		t.Fatalf("gauge = %v, want 1", got)
+	}
+
+	// The second decrement drops the count to zero, which should prune the
+	// room: roomConnCount must no longer track it.
+	DecWebsocketConnections(room)
+	roomConnMu.Lock()
+	_, tracked := roomConnCount[room]
+	roomConnMu.Unlock()
+	if tracked {
+		t.Fatalf("roomConnCount still tracks %q after it reached zero", room)
+	}
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/synctv-org/synctv/internal/db"
+	"github.com/synctv-org/synctv/internal/metrics"
 	"github.com/synctv-org/synctv/server/model"
 )
 
@@ -16,39 +20,69 @@ func Health(ctx *gin.Context) {
 	})
 }
 
-// Ready returns readiness check endpoint
-// Checks if all dependencies (database) are accessible
+// maxConsecutiveKeepAliveFailures is the number of consecutive failed
+// background keepalive checks at which Ready reports the database unhealthy.
+const maxConsecutiveKeepAliveFailures = 3
+
+// Ready returns readiness check endpoint.
+// Consults db.Health, the keepalive registry populated by the goroutine
+// db.Init starts (see the healthStatus doc comment in package db for why
+// this avoids a synchronous Ping on every probe), and
+// falls back to a direct ping only when the keepalive goroutine is disabled.
 func Ready(ctx *gin.Context) {
-	// Check database connectivity
 	sqlDB, err := db.DB().DB()
 	if err != nil {
 		ctx.JSON(http.StatusServiceUnavailable, model.NewAPIErrorResp(err))
 		return
 	}
 
-	if err := sqlDB.Ping(); err != nil {
-		ctx.JSON(http.StatusServiceUnavailable, model.NewAPIErrorResp(err))
-		return
-	}
-
-	// Check database connection pool health
+	health := db.Health()
 	stats := sqlDB.Stats()
-	if stats.OpenConnections == 0 {
-		ctx.JSON(http.StatusServiceUnavailable, gin.H{
-			"status": "unhealthy",
-			"reason": "no database connections available",
-		})
-		return
-	}
+	pool := db.ResolvedPool()
+	driver := db.ResolvedDriver()
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"status": "ready",
+	resp := gin.H{
+		"keep_alive_enabled":   health.Enabled,
+		"last_ok":              health.LastOK,
+		"consecutive_failures": health.ConsecutiveFailures,
 		"database": gin.H{
 			"open_connections": stats.OpenConnections,
 			"in_use":           stats.InUse,
 			"idle":             stats.Idle,
+			"max_open_conns":   pool.MaxOpenConns,
+			"max_idle_conns":   pool.MaxIdleConns,
+			"driver":           driver.Type,
+			"driver_version":   driver.Version,
 		},
-	})
+	}
+
+	// When the keepalive goroutine is disabled (KeepAliveInterval <= 0),
+	// LastOK is never populated, so fall back to a direct ping instead of
+	// treating a permanently-zero LastOK as unhealthy.
+	if !health.Enabled {
+		pingCtx, cancel := context.WithTimeout(ctx.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := sqlDB.PingContext(pingCtx); err != nil {
+			resp["status"] = "unhealthy"
+			resp["reason"] = err.Error()
+			ctx.JSON(http.StatusServiceUnavailable, resp)
+			return
+		}
+
+		resp["status"] = "ready"
+		ctx.JSON(http.StatusOK, resp)
+		return
+	}
+
+	if health.LastOK.IsZero() || health.ConsecutiveFailures >= maxConsecutiveKeepAliveFailures {
+		resp["status"] = "unhealthy"
+		ctx.JSON(http.StatusServiceUnavailable, resp)
+		return
+	}
+
+	resp["status"] = "ready"
+	ctx.JSON(http.StatusOK, resp)
 }
 
 // Live returns liveness check endpoint (Kubernetes liveness probe)
@@ -58,3 +92,28 @@ func Live(ctx *gin.Context) {
 		"status": "alive",
 	})
 }
+
+// metricsHandler serves the process' Prometheus registry, including the
+// HTTP, websocket, movie-proxy and database connection pool metrics
+// registered by the internal/metrics package.
+var metricsHandler = promhttp.Handler()
+
+// Metrics exposes Prometheus metrics for scraping.
+// Mount this on its own route (e.g. GET /metrics) rather than under the
+// authenticated API group.
+func Metrics(ctx *gin.Context) {
+	metricsHandler.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+// RegisterHealthRoutes mounts the health/readiness/liveness/metrics
+// handlers on r and attaches metrics.GinMiddleware so every request routed
+// through r is counted and timed. Call this once from the server setup
+// alongside the rest of the route registration.
+func RegisterHealthRoutes(r gin.IRouter) {
+	r.Use(metrics.GinMiddleware())
+
+	r.GET("/healthz", Health)
+	r.GET("/readyz", Ready)
+	r.GET("/livez", Live)
+	r.GET("/metrics", Metrics)
+}
@@ -0,0 +1,19 @@
+// Package server assembles the gin engine serving synctv's HTTP API.
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/synctv-org/synctv/server/handlers"
+)
+
+// NewRouter builds the gin engine for the process, wiring in the
+// health/readiness/liveness/metrics routes (and the request-metrics
+// middleware they bring along). It registers no other routes.
+func NewRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	handlers.RegisterHealthRoutes(r)
+
+	return r
+}